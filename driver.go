@@ -0,0 +1,132 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Wrap inspects anything and returns the RunnerContext and
+// PlaceholderFormat that match it: *sql.DB gets StdSqlCtx with the format
+// inferred from the driver registered with database/sql, and
+// *pgx.Conn/*pgxpool.Pool/pgx.Tx get WrapPgx with Dollar. This eliminates a
+// whole class of "pq: syntax error" bugs that show up when code moves
+// between lib/pq and pgx without updating the placeholder style by hand.
+//
+// A bare *sql.Tx can't expose the driver it was opened against, so it gets
+// Question here, which is wrong for a transaction opened against
+// postgres/pgx -- the exact case this function exists to get right. Call
+// WrapTx instead when the originating *sql.DB is available, which it almost
+// always is at the call site that opened the transaction; with only the
+// driver name in hand, PlaceholderFormatFor gives the matching format.
+func Wrap(anything interface{}) (RunnerContext, PlaceholderFormat, error) {
+	switch db := anything.(type) {
+	case *sql.DB:
+		return WrapStdSqlCtx(db), PlaceholderFormatFor(driverNameFor(db.Driver())), nil
+	case *sql.Tx:
+		return WrapStdSqlCtx(db), Question, nil
+	case *pgx.Conn:
+		return WrapPgx(pgxQueryContextAdapter{db}), Dollar, nil
+	case *pgxpool.Pool:
+		return WrapPgx(pgxQueryContextAdapter{db}), Dollar, nil
+	case pgx.Tx:
+		return WrapPgx(pgxQueryContextAdapter{db}), Dollar, nil
+	default:
+		return nil, nil, fmt.Errorf("squirrel: Wrap: unsupported db type %T", anything)
+	}
+}
+
+// WrapTx wraps tx the way Wrap would, but infers the PlaceholderFormat from
+// db -- the *sql.DB tx was started from -- rather than defaulting to
+// Question. Use this instead of Wrap whenever the originating *sql.DB is
+// available, which covers the common case of a transaction opened against
+// postgres via lib/pq or pgx's database/sql driver.
+func WrapTx(tx *sql.Tx, db *sql.DB) (RunnerContext, PlaceholderFormat) {
+	return WrapStdSqlCtx(tx), PlaceholderFormatFor(driverNameFor(db.Driver()))
+}
+
+// pgxQuerier is satisfied by *pgx.Conn, *pgxpool.Pool, and pgx.Tx: each
+// exposes Query/Exec/QueryRow without a "Context" suffix, since every
+// method already takes a context.Context.
+type pgxQuerier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// pgxQueryContextAdapter adapts a pgxQuerier to the Pgx interface in
+// squirrel_ctx.go, which names its query method QueryContext rather than
+// Query.
+type pgxQueryContextAdapter struct {
+	pgxQuerier
+}
+
+func (a pgxQueryContextAdapter) QueryContext(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	return a.pgxQuerier.Query(ctx, query, args...)
+}
+
+// driverNameFor best-efforts the name db.Driver() was registered under, by
+// comparing its type against every driver registered via sql.Register --
+// the same trick sqlx and friends use, since database/sql never exposes
+// the name a *sql.DB was opened with.
+func driverNameFor(d driver.Driver) string {
+	want := reflect.TypeOf(d)
+	for _, name := range sql.Drivers() {
+		probe, err := sql.Open(name, "")
+		if err != nil {
+			continue
+		}
+		match := reflect.TypeOf(probe.Driver()) == want
+		_ = probe.Close()
+		if match {
+			return name
+		}
+	}
+	return ""
+}
+
+// PlaceholderFormatFor maps a database/sql driver name to the
+// PlaceholderFormat it expects, mirroring sqlx's BindType table.
+func PlaceholderFormatFor(driverName string) PlaceholderFormat {
+	switch driverName {
+	case "postgres", "pgx", "pq-timeouts", "cloudsqlpostgres":
+		return Dollar
+	case "oci8", "ora", "goracle", "godror":
+		return Colon
+	case "sqlserver":
+		return AtP
+	default:
+		return Question
+	}
+}
+
+// For wraps db with Wrap and sets b's PlaceholderFormat to the result, so
+// a chain started from it rebinds correctly regardless of whether db is a
+// *sql.DB or a pgx connection/pool:
+//
+//	sq.StatementBuilder.For(db).Select("id").From("users")
+//
+// If db isn't a type Wrap recognizes, b is returned with its
+// PlaceholderFormat unchanged; For has no way to surface that error
+// itself, since StatementBuilderType carries no error state of its own.
+//
+// For only sets the PlaceholderFormat -- it does not also hand back a
+// RunnerContext bound to the chain. GetContext/SelectContext (see
+// scan.go) need an explicit RunnerContext argument because
+// SelectBuilder/InsertBuilder/etc. don't have a field to hold one, so get
+// the runner with Wrap(db) (or WrapTx(tx, db) for a transaction) alongside
+// For and pass it to GetContext/SelectContext the same as you would
+// without For.
+func (b StatementBuilderType) For(db interface{}) StatementBuilderType {
+	_, format, err := Wrap(db)
+	if err != nil {
+		return b
+	}
+	return b.PlaceholderFormat(format)
+}
@@ -0,0 +1,117 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestPlaceholderFormatFor(t *testing.T) {
+	cases := map[string]PlaceholderFormat{
+		"postgres":  Dollar,
+		"pgx":       Dollar,
+		"godror":    Colon,
+		"sqlserver": AtP,
+		"sqlite3":   Question,
+		"":          Question,
+	}
+	for driverName, want := range cases {
+		if got := PlaceholderFormatFor(driverName); got != want {
+			t.Errorf("PlaceholderFormatFor(%q) = %v, want %v", driverName, got, want)
+		}
+	}
+}
+
+// fakePgxQuerier is a minimal pgxQuerier used to confirm
+// pgxQueryContextAdapter forwards QueryContext to Query.
+type fakePgxQuerier struct {
+	queried bool
+}
+
+func (f *fakePgxQuerier) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	f.queried = true
+	return nil, errors.New("fake")
+}
+
+func (f *fakePgxQuerier) Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+func (f *fakePgxQuerier) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return nil
+}
+
+func TestPgxQueryContextAdapterForwardsToQuery(t *testing.T) {
+	f := &fakePgxQuerier{}
+	a := pgxQueryContextAdapter{f}
+	_, _ = a.QueryContext(context.Background(), "select 1")
+	if !f.queried {
+		t.Fatal("expected QueryContext to call the underlying Query method")
+	}
+}
+
+// fakeDriver/fakeConn/fakeTx are the minimal database/sql driver pieces
+// needed to open a *sql.DB and start a *sql.Tx from it without a real
+// database, so WrapTx can be tested end to end.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not implemented") }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func TestWrapTxInfersPlaceholderFormatFromOriginatingDB(t *testing.T) {
+	sql.Register("postgres", fakeDriver{})
+	db, err := sql.Open("postgres", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("db.Begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, format := WrapTx(tx, db)
+	if format != Dollar {
+		t.Fatalf("WrapTx format = %v, want Dollar (inferred from the \"postgres\" driver db was opened with)", format)
+	}
+}
+
+func TestWrapOfSqlTxDefaultsToQuestion(t *testing.T) {
+	sql.Register("squirrel_test_tx_question", fakeDriver{})
+	db, err := sql.Open("squirrel_test_tx_question", "")
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("db.Begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, format, err := Wrap(tx)
+	if err != nil {
+		t.Fatalf("Wrap: %v", err)
+	}
+	if format != Question {
+		t.Fatalf("Wrap(*sql.Tx) format = %v, want Question (the documented limitation -- use WrapTx to do better)", format)
+	}
+}
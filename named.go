@@ -0,0 +1,208 @@
+package squirrel
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// InExpr is the Sqlizer form of In's rewrite: a "?" placeholder bound to a
+// slice expanded into one "?" per element, with that slice flattened into
+// the argument list. In builds one internally but returns its SQL and args
+// rather than the InExpr itself, and no exported function returns an
+// InExpr.
+type InExpr struct {
+	sql  string
+	args []interface{}
+}
+
+// ToSql implements Sqlizer.
+func (e InExpr) ToSql() (string, []interface{}, error) {
+	return e.sql, e.args, nil
+}
+
+// In rewrites each "?" placeholder in query whose corresponding value in
+// args is a slice (other than []byte) into a comma-separated run of "?" of
+// the same length, flattening that slice's elements into the returned
+// args. Non-slice args pass through unchanged. It errors if a slice arg is
+// empty, since there's no SQL that means "matches nothing" without
+// changing the shape of the query.
+func In(query string, args ...interface{}) (string, []interface{}, error) {
+	expr, err := buildInExpr(query, args...)
+	if err != nil {
+		return "", nil, err
+	}
+	return expr.sql, expr.args, nil
+}
+
+func buildInExpr(query string, args ...interface{}) (InExpr, error) {
+	var buf strings.Builder
+	newArgs := make([]interface{}, 0, len(args))
+	argIdx := 0
+	n := len(query)
+	for i := 0; i < n; i++ {
+		c := query[i]
+		if c == '\'' || c == '"' {
+			j := skipQuoted(query, i)
+			buf.WriteString(query[i:j])
+			i = j - 1
+			continue
+		}
+		if c != '?' {
+			buf.WriteByte(c)
+			continue
+		}
+		if argIdx >= len(args) {
+			return InExpr{}, fmt.Errorf("squirrel: In: query has more placeholders than the %d args given", len(args))
+		}
+		arg := args[argIdx]
+		argIdx++
+
+		v := reflect.ValueOf(arg)
+		if arg != nil && v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+			n := v.Len()
+			if n == 0 {
+				return InExpr{}, errors.New("squirrel: In: empty slice passed in, cannot expand '?'")
+			}
+			for j := 0; j < n; j++ {
+				if j > 0 {
+					buf.WriteByte(',')
+				}
+				buf.WriteByte('?')
+				newArgs = append(newArgs, v.Index(j).Interface())
+			}
+			continue
+		}
+		buf.WriteByte('?')
+		newArgs = append(newArgs, arg)
+	}
+	if argIdx != len(args) {
+		return InExpr{}, fmt.Errorf("squirrel: In: %d args given but query only has %d placeholders", len(args), argIdx)
+	}
+	return InExpr{sql: buf.String(), args: newArgs}, nil
+}
+
+// NamedExpr is the Sqlizer form of Named's rewrite: ":name" tokens in a
+// query replaced with positional "?" placeholders, with argument values
+// pulled from a struct (by "db" tag) or a map[string]interface{} (by key),
+// in the order the names appear. Named builds one internally but returns
+// its SQL and args rather than the NamedExpr itself, and no exported
+// function returns a NamedExpr.
+type NamedExpr struct {
+	sql  string
+	args []interface{}
+}
+
+// ToSql implements Sqlizer.
+func (e NamedExpr) ToSql() (string, []interface{}, error) {
+	return e.sql, e.args, nil
+}
+
+// Named rewrites ":name" tokens in query into positional "?" placeholders
+// and returns the matching args, extracted from arg (a struct or a
+// map[string]interface{}) in the order the names appear. Tokens inside
+// single- or double-quoted string literals, and "::" type casts, are left
+// untouched.
+func Named(query string, arg interface{}) (string, []interface{}, error) {
+	expr, err := buildNamedExpr(query, arg)
+	if err != nil {
+		return "", nil, err
+	}
+	return expr.sql, expr.args, nil
+}
+
+func buildNamedExpr(query string, arg interface{}) (NamedExpr, error) {
+	names, sql := scanNamedTokens(query)
+	args := make([]interface{}, len(names))
+
+	if m, ok := arg.(map[string]interface{}); ok {
+		for i, name := range names {
+			val, ok := m[name]
+			if !ok {
+				return NamedExpr{}, fmt.Errorf("squirrel: Named: no value for :%s", name)
+			}
+			args[i] = val
+		}
+		return NamedExpr{sql: sql, args: args}, nil
+	}
+
+	rv := reflect.ValueOf(arg)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return NamedExpr{}, errors.New("squirrel: Named: arg must be a struct or a map[string]interface{}")
+	}
+	fields := structFieldsByColumn(rv.Type())
+	for i, name := range names {
+		idx, ok := fields[name]
+		if !ok {
+			return NamedExpr{}, fmt.Errorf("squirrel: Named: no field for :%s", name)
+		}
+		args[i] = rv.FieldByIndex(idx).Interface()
+	}
+	return NamedExpr{sql: sql, args: args}, nil
+}
+
+// skipQuoted returns the index just past the quoted string literal that
+// starts at i (where query[i] is the opening ' or "), honoring \-escapes,
+// so callers can copy it verbatim without mistaking its contents for
+// placeholders or named-parameter tokens. Shared by buildInExpr and
+// scanNamedTokens.
+func skipQuoted(query string, i int) int {
+	q := query[i]
+	n := len(query)
+	j := i + 1
+	for j < n && query[j] != q {
+		if query[j] == '\\' && j+1 < n {
+			j++
+		}
+		j++
+	}
+	if j < n {
+		j++ // include closing quote
+	}
+	return j
+}
+
+// scanNamedTokens walks query, replacing each ":name" token with "?" and
+// collecting the names in order of appearance. It skips over single- and
+// double-quoted string literals and "::" casts so neither is mistaken for
+// a bind parameter.
+func scanNamedTokens(query string) ([]string, string) {
+	var names []string
+	var buf strings.Builder
+	n := len(query)
+	for i := 0; i < n; i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			j := skipQuoted(query, i)
+			buf.WriteString(query[i:j])
+			i = j - 1
+		case c == ':' && i+1 < n && query[i+1] == ':':
+			buf.WriteString("::")
+			i++
+		case c == ':' && i+1 < n && isNameStart(query[i+1]):
+			j := i + 1
+			for j < n && isNameChar(query[j]) {
+				j++
+			}
+			names = append(names, query[i+1:j])
+			buf.WriteByte('?')
+			i = j - 1
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	return names, buf.String()
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,72 @@
+package squirrel
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInExpandsSlice(t *testing.T) {
+	query, args, err := In("id IN (?) AND active = ?", []int{1, 2, 3}, true)
+	if err != nil {
+		t.Fatalf("In: %v", err)
+	}
+	if query != "id IN (?,?,?) AND active = ?" {
+		t.Fatalf("got query %q", query)
+	}
+	want := []interface{}{1, 2, 3, true}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("got args %v, want %v", args, want)
+	}
+}
+
+func TestInIgnoresQuestionMarkInsideStringLiteral(t *testing.T) {
+	query, args, err := In("note = 'what?' AND id IN (?)", []int{1, 2})
+	if err != nil {
+		t.Fatalf("In: %v", err)
+	}
+	if query != "note = 'what?' AND id IN (?,?)" {
+		t.Fatalf("got query %q", query)
+	}
+	want := []interface{}{1, 2}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("got args %v, want %v", args, want)
+	}
+}
+
+func TestInErrorsOnEmptySlice(t *testing.T) {
+	if _, _, err := In("id IN (?)", []int{}); err == nil {
+		t.Fatal("expected an error for an empty slice")
+	}
+}
+
+func TestNamedRewritesTokensAndExtractsFromStruct(t *testing.T) {
+	type params struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	query, args, err := Named("id = :id AND name = :name", params{ID: 1, Name: "alice"})
+	if err != nil {
+		t.Fatalf("Named: %v", err)
+	}
+	if query != "id = ? AND name = ?" {
+		t.Fatalf("got query %q", query)
+	}
+	want := []interface{}{1, "alice"}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("got args %v, want %v", args, want)
+	}
+}
+
+func TestNamedSkipsCastsAndStringLiterals(t *testing.T) {
+	query, args, err := Named("val::int = :val AND note = ':not_a_token'", map[string]interface{}{"val": 3})
+	if err != nil {
+		t.Fatalf("Named: %v", err)
+	}
+	if query != "val::int = ? AND note = ':not_a_token'" {
+		t.Fatalf("got query %q", query)
+	}
+	want := []interface{}{3}
+	if !reflect.DeepEqual(args, want) {
+		t.Fatalf("got args %v, want %v", args, want)
+	}
+}
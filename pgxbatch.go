@@ -0,0 +1,118 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Batcher is implemented by types that can open a pgx.Batch to pipeline
+// several statements in a single round trip.
+type Batcher interface {
+	BeginBatch() *pgx.Batch
+}
+
+// PgxBatch accumulates Sqlizer statements to send to postgres as a single
+// pipelined pgx.Batch.
+type PgxBatch struct {
+	batch *pgx.Batch
+	n     int
+}
+
+// NewPgxBatch returns an empty PgxBatch.
+func NewPgxBatch() *PgxBatch {
+	return &PgxBatch{batch: &pgx.Batch{}}
+}
+
+// BeginBatch implements Batcher.
+func (b *PgxBatch) BeginBatch() *pgx.Batch {
+	return b.batch
+}
+
+// Queue builds s and appends it to the batch. Statements run, in the order
+// queued, once the batch is sent with SendBatch.
+func (b *PgxBatch) Queue(s Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	b.batch.Queue(query, args...)
+	b.n++
+	return nil
+}
+
+// Len returns the number of statements queued so far.
+func (b *PgxBatch) Len() int {
+	return b.n
+}
+
+// BatchConn is the subset of a pgx connection or pool needed to send a
+// batch. *pgx.Conn, *pgxpool.Pool, and pgx.Tx all implement it natively --
+// unlike Pgx, BatchConn is satisfied directly by the real pgx types, so
+// SendBatch/BatchExecContextWith take it instead of routing through the
+// QueryContext-only Pgx adapter, which never has SendBatch promoted onto
+// it.
+type BatchConn interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// BatchResults wraps pgx.BatchResults, returning our Rows/RowScanner types
+// so callers don't need to import pgx directly. Results must be read in
+// the order the statements were queued, and Close must be called once
+// they've all been consumed.
+type BatchResults struct {
+	pgx.BatchResults
+}
+
+// SendBatch submits b to conn and returns a BatchResults giving access to
+// each queued statement's result, in queue order.
+func (b *PgxBatch) SendBatch(ctx context.Context, conn BatchConn) *BatchResults {
+	return &BatchResults{BatchResults: conn.SendBatch(ctx, b.batch)}
+}
+
+// Exec reads the result of the next queued statement as an Exec.
+func (r *BatchResults) Exec() (sql.Result, error) {
+	ct, err := r.BatchResults.Exec()
+	return commandTagWrapper{ct}, err
+}
+
+// Query reads the result of the next queued statement as a Query.
+func (r *BatchResults) Query() (Rows, error) {
+	rows, err := r.BatchResults.Query()
+	if err != nil {
+		return nil, err
+	}
+	return PgxRowsWrapper{rows}, nil
+}
+
+// QueryRow reads the result of the next queued statement as a QueryRow.
+func (r *BatchResults) QueryRow() RowScanner {
+	return r.BatchResults.QueryRow()
+}
+
+// BatchExecContextWith builds every statement in sqls up front, submits
+// them to conn (a *pgx.Conn, *pgxpool.Pool, or pgx.Tx) as a single pgx
+// batch, and Execs each result in order, giving callers a single round
+// trip for bulk inserts/updates built with squirrel.
+func BatchExecContextWith(ctx context.Context, conn BatchConn, sqls ...Sqlizer) ([]sql.Result, error) {
+	b := NewPgxBatch()
+	for _, s := range sqls {
+		if err := b.Queue(s); err != nil {
+			return nil, err
+		}
+	}
+
+	res := b.SendBatch(ctx, conn)
+	defer res.Close()
+
+	results := make([]sql.Result, len(sqls))
+	for i := range sqls {
+		r, err := res.Exec()
+		if err != nil {
+			return nil, err
+		}
+		results[i] = r
+	}
+	return results, nil
+}
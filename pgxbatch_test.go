@@ -0,0 +1,123 @@
+package squirrel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeSqlizer is a trivial Sqlizer for exercising PgxBatch.Queue without a
+// real SelectBuilder/InsertBuilder.
+type fakeSqlizer struct {
+	sql  string
+	args []interface{}
+	err  error
+}
+
+func (s fakeSqlizer) ToSql() (string, []interface{}, error) {
+	return s.sql, s.args, s.err
+}
+
+// fakeBatchConn is a BatchConn that records the batch it was sent and
+// returns a scripted fakeBatchResults, letting tests exercise
+// BatchExecContextWith without a real pgx connection.
+type fakeBatchConn struct {
+	sent    *pgx.Batch
+	results *fakeBatchResults
+}
+
+func (c *fakeBatchConn) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	c.sent = b
+	return c.results
+}
+
+// fakeBatchResults returns a scripted command tag for each Exec call, in
+// order, and an error once the scripted results run out.
+type fakeBatchResults struct {
+	tags   []pgconn.CommandTag
+	i      int
+	closed bool
+}
+
+func (r *fakeBatchResults) Exec() (pgconn.CommandTag, error) {
+	if r.i >= len(r.tags) {
+		return pgconn.CommandTag{}, errors.New("fakeBatchResults: no more queued results")
+	}
+	tag := r.tags[r.i]
+	r.i++
+	return tag, nil
+}
+
+func (r *fakeBatchResults) Query() (pgx.Rows, error) { return nil, errors.New("not implemented") }
+func (r *fakeBatchResults) QueryRow() pgx.Row        { return nil }
+func (r *fakeBatchResults) Close() error             { r.closed = true; return nil }
+
+func TestPgxBatchQueueBuildsAndCountsStatements(t *testing.T) {
+	b := NewPgxBatch()
+	if err := b.Queue(fakeSqlizer{sql: "insert into t values ($1)", args: []interface{}{1}}); err != nil {
+		t.Fatalf("Queue: %v", err)
+	}
+	if err := b.Queue(fakeSqlizer{sql: "insert into t values ($1)", args: []interface{}{2}}); err != nil {
+		t.Fatalf("Queue: %v", err)
+	}
+	if b.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", b.Len())
+	}
+	if b.BeginBatch().Len() != 2 {
+		t.Fatalf("underlying pgx.Batch has %d queued items, want 2", b.BeginBatch().Len())
+	}
+}
+
+func TestPgxBatchQueuePropagatesToSqlError(t *testing.T) {
+	b := NewPgxBatch()
+	wantErr := errors.New("boom")
+	if err := b.Queue(fakeSqlizer{err: wantErr}); err != wantErr {
+		t.Fatalf("Queue: got %v, want %v", err, wantErr)
+	}
+}
+
+func TestBatchExecContextWithRunsEachStatementInOrder(t *testing.T) {
+	conn := &fakeBatchConn{results: &fakeBatchResults{
+		tags: []pgconn.CommandTag{
+			pgconn.NewCommandTag("INSERT 0 1"),
+			pgconn.NewCommandTag("INSERT 0 1"),
+		},
+	}}
+
+	results, err := BatchExecContextWith(context.Background(), conn,
+		fakeSqlizer{sql: "insert into t values ($1)", args: []interface{}{1}},
+		fakeSqlizer{sql: "insert into t values ($1)", args: []interface{}{2}},
+	)
+	if err != nil {
+		t.Fatalf("BatchExecContextWith: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	for i, r := range results {
+		if n, _ := r.RowsAffected(); n != 1 {
+			t.Errorf("result %d: RowsAffected() = %d, want 1", i, n)
+		}
+	}
+	if conn.sent == nil || conn.sent.Len() != 2 {
+		t.Fatalf("expected a batch with 2 queued statements sent to conn")
+	}
+	if !conn.results.closed {
+		t.Fatalf("expected BatchExecContextWith to Close the BatchResults")
+	}
+}
+
+func TestBatchExecContextWithStopsOnBuildError(t *testing.T) {
+	conn := &fakeBatchConn{results: &fakeBatchResults{}}
+	wantErr := errors.New("bad sqlizer")
+	_, err := BatchExecContextWith(context.Background(), conn, fakeSqlizer{err: wantErr})
+	if err != wantErr {
+		t.Fatalf("got %v, want %v", err, wantErr)
+	}
+	if conn.sent != nil {
+		t.Fatalf("expected BatchExecContextWith not to reach SendBatch")
+	}
+}
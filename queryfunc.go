@@ -0,0 +1,44 @@
+package squirrel
+
+import "context"
+
+// QueryFuncRow is the per-row argument passed to the callback in
+// QueryFuncContextWith. It mirrors pgx v4's QueryFunc row argument.
+type QueryFuncRow interface {
+	// Columns returns the names of the columns in the result set, in
+	// query order.
+	Columns() ([]string, error)
+}
+
+// RawValueser is implemented by Rows that can expose the unconverted
+// driver values for the current row without paying for a Scan.
+// PgxRowsWrapper implements it; StdRowsWrapper does not, since
+// database/sql never exposes raw values.
+type RawValueser interface {
+	RawValues() [][]byte
+}
+
+// QueryFuncContextWith runs the SQL built by s against db and, for each
+// resulting row, Scans into scans and then invokes fn with the Rows as a
+// QueryFuncRow. It stops and returns the first error from either Scan or
+// fn, and always closes the Rows.
+func QueryFuncContextWith(ctx context.Context, db QueryerContext, s Sqlizer, scans []interface{}, fn func(QueryFuncRow) error) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(scans...); err != nil {
+			return err
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,78 @@
+package squirrel
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeQueryerContext struct {
+	rows Rows
+	err  error
+}
+
+func (f fakeQueryerContext) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	return f.rows, f.err
+}
+
+func TestQueryFuncContextWithScansAndInvokesCallback(t *testing.T) {
+	rows := &fakeRows{
+		columns: []string{"id", "name"},
+		data:    [][]interface{}{{1, "alice"}, {2, "bob"}},
+	}
+	db := fakeQueryerContext{rows: rows}
+
+	var names []string
+	var id int
+	var name string
+	err := QueryFuncContextWith(context.Background(), db, fakeSqlizer{sql: "select id, name from t"},
+		[]interface{}{&id, &name}, func(r QueryFuncRow) error {
+			cols, cerr := r.Columns()
+			if cerr != nil {
+				return cerr
+			}
+			if len(cols) != 2 {
+				t.Fatalf("got %d columns, want 2", len(cols))
+			}
+			names = append(names, name)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("QueryFuncContextWith: %v", err)
+	}
+	if len(names) != 2 || names[0] != "alice" || names[1] != "bob" {
+		t.Fatalf("got %v", names)
+	}
+}
+
+func TestQueryFuncContextWithStopsOnCallbackError(t *testing.T) {
+	rows := &fakeRows{
+		columns: []string{"id", "name"},
+		data:    [][]interface{}{{1, "alice"}, {2, "bob"}},
+	}
+	db := fakeQueryerContext{rows: rows}
+	wantErr := errors.New("stop")
+	var id int
+	var name string
+	calls := 0
+	err := QueryFuncContextWith(context.Background(), db, fakeSqlizer{sql: "select id, name from t"},
+		[]interface{}{&id, &name}, func(QueryFuncRow) error {
+			calls++
+			return wantErr
+		})
+	if err != wantErr {
+		t.Fatalf("got %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Fatalf("callback invoked %d times, want 1", calls)
+	}
+}
+
+func TestQueryFuncContextWithPropagatesToSqlError(t *testing.T) {
+	wantErr := errors.New("bad sqlizer")
+	err := QueryFuncContextWith(context.Background(), fakeQueryerContext{}, fakeSqlizer{err: wantErr}, nil,
+		func(QueryFuncRow) error { return nil })
+	if err != wantErr {
+		t.Fatalf("got %v, want %v", err, wantErr)
+	}
+}
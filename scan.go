@@ -0,0 +1,256 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// scanPlan maps each column position in a result set to the struct field
+// that should receive the scanned value, resolved once per distinct
+// (reflect.Type, columns) pair and cached for reuse across query
+// executions.
+type scanPlan struct {
+	fields [][]int
+}
+
+var scanPlanCache sync.Map // map[scanPlanKey]*scanPlan
+
+type scanPlanKey struct {
+	typ     reflect.Type
+	columns string
+}
+
+var scannerType = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+var timeType = reflect.TypeOf(time.Time{})
+
+func scanPlanFor(t reflect.Type, columns []string) *scanPlan {
+	key := scanPlanKey{typ: t, columns: strings.Join(columns, "\x00")}
+	if v, ok := scanPlanCache.Load(key); ok {
+		return v.(*scanPlan)
+	}
+	byColumn := structFieldsByColumn(t)
+	plan := &scanPlan{fields: make([][]int, len(columns))}
+	for i, c := range columns {
+		plan.fields[i] = byColumn[strings.ToLower(c)]
+	}
+	scanPlanCache.Store(key, plan)
+	return plan
+}
+
+// structFieldsByColumn walks t, descending into anonymous embedded structs
+// that aren't themselves sql.Scanner, and indexes each scannable field by
+// its "db" tag, falling back to the snake_cased field name.
+func structFieldsByColumn(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" && !f.Anonymous {
+				continue // unexported
+			}
+			idx := append(append([]int{}, index...), i)
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if f.Anonymous && ft.Kind() == reflect.Struct && ft != timeType &&
+				!ft.Implements(scannerType) && !reflect.PtrTo(ft).Implements(scannerType) {
+				walk(ft, idx)
+				continue
+			}
+			name := f.Tag.Get("db")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = toSnakeCase(f.Name)
+			}
+			fields[strings.ToLower(name)] = idx
+		}
+	}
+	walk(t, nil)
+	return fields
+}
+
+// toSnakeCase converts a Go field name to its snake_case column name, the
+// same way sqlx/gorm's default name mappers do: a run of consecutive
+// uppercase letters (as in "ID" or "URL") is treated as a single word
+// rather than one letter per word, so "UserID" becomes "user_id", not
+// "user_i_d". A new word starts at a capital that follows a lowercase
+// letter or digit, or at the last capital of an uppercase run when a
+// lowercase letter follows it, so "HTTPServer" becomes "http_server".
+func toSnakeCase(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+	for i, r := range runes {
+		if r >= 'A' && r <= 'Z' && i > 0 {
+			prev := runes[i-1]
+			switch {
+			case prev >= 'a' && prev <= 'z':
+				b.WriteByte('_')
+			case prev >= '0' && prev <= '9':
+				b.WriteByte('_')
+			case prev >= 'A' && prev <= 'Z' && i+1 < len(runes) && runes[i+1] >= 'a' && runes[i+1] <= 'z':
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToLower(b.String())
+}
+
+// GetContext executes the SQL built by s against db and scans the single
+// resulting row into dest, a pointer to a struct or a scalar. It returns
+// sql.ErrNoRows if the query yields no rows.
+func GetContext(ctx context.Context, db RunnerContext, dest interface{}, s Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return scanOne(rows, dest)
+}
+
+// SelectContext executes the SQL built by s against db and scans every
+// resulting row into dest, a pointer to a slice of structs or of pointers
+// to structs.
+func SelectContext(ctx context.Context, db RunnerContext, dest interface{}, s Sqlizer) error {
+	query, args, err := s.ToSql()
+	if err != nil {
+		return err
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	return scanAll(rows, dest)
+}
+
+// Get is the non-context equivalent of GetContext.
+func Get(db Runner, dest interface{}, s Sqlizer) error {
+	return GetContext(context.Background(), runnerAdapter{db}, dest, s)
+}
+
+// SelectInto is the non-context equivalent of SelectContext. It's named
+// SelectInto, not Select, so it doesn't collide with the package-level
+// Select(columns ...string) SelectBuilder in statement.go.
+func SelectInto(db Runner, dest interface{}, s Sqlizer) error {
+	return SelectContext(context.Background(), runnerAdapter{db}, dest, s)
+}
+
+// runnerAdapter lets a plain Runner satisfy RunnerContext by ignoring the
+// context on every call.
+type runnerAdapter struct {
+	Runner
+}
+
+func (r runnerAdapter) QueryContext(_ context.Context, query string, args ...interface{}) (Rows, error) {
+	rows, err := r.Runner.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return StdRowsWrapper{rows}, nil
+}
+
+func (r runnerAdapter) QueryRowContext(_ context.Context, query string, args ...interface{}) RowScanner {
+	return r.Runner.QueryRow(query, args...)
+}
+
+func (r runnerAdapter) ExecContext(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.Runner.Exec(query, args...)
+}
+
+func scanOne(rows Rows, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return errors.New("squirrel: Get destination must be a non-nil pointer")
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return sql.ErrNoRows
+	}
+	if err := scanRow(rows, v.Elem()); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+func scanAll(rows Rows, dest interface{}) error {
+	v := reflect.ValueOf(dest)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return errors.New("squirrel: Select destination must be a pointer to a slice")
+	}
+	slice := v.Elem()
+	elemType := slice.Type().Elem()
+	ptrElems := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if ptrElems {
+		structType = elemType.Elem()
+	}
+	for rows.Next() {
+		elem := reflect.New(structType)
+		if err := scanRow(rows, elem.Elem()); err != nil {
+			return err
+		}
+		if ptrElems {
+			slice = reflect.Append(slice, elem)
+		} else {
+			slice = reflect.Append(slice, elem.Elem())
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	v.Elem().Set(slice)
+	return nil
+}
+
+// scanRow scans the current row of rows into target, which is either a
+// scalar/Scanner value or a struct whose fields are resolved via
+// scanPlanFor.
+func scanRow(rows Rows, target reflect.Value) error {
+	if target.Kind() != reflect.Struct || target.Type() == timeType ||
+		target.Addr().Type().Implements(scannerType) {
+		return rows.Scan(target.Addr().Interface())
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	plan := scanPlanFor(target.Type(), columns)
+	dest := make([]interface{}, len(columns))
+	var discard interface{}
+	for i, idx := range plan.fields {
+		if idx == nil {
+			dest[i] = &discard
+			continue
+		}
+		dest[i] = target.FieldByIndex(idx).Addr().Interface()
+	}
+	return rows.Scan(dest...)
+}
+
+// GetContext executes the select and scans the single resulting row into
+// dest. See the package-level GetContext for the scanning rules.
+func (b SelectBuilder) GetContext(ctx context.Context, db RunnerContext, dest interface{}) error {
+	return GetContext(ctx, db, dest, b)
+}
+
+// SelectContext executes the select and scans every resulting row into
+// dest. See the package-level SelectContext for the scanning rules.
+func (b SelectBuilder) SelectContext(ctx context.Context, db RunnerContext, dest interface{}) error {
+	return SelectContext(ctx, db, dest, b)
+}
@@ -0,0 +1,132 @@
+package squirrel
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+// fakeRows is a minimal Rows implementation backed by in-memory data, used
+// to test scanOne/scanAll without a real database/sql or pgx driver.
+type fakeRows struct {
+	columns []string
+	data    [][]interface{}
+	pos     int
+}
+
+func (r *fakeRows) Columns() ([]string, error) { return r.columns, nil }
+func (r *fakeRows) Close()                     {}
+func (r *fakeRows) Err() error                 { return nil }
+
+func (r *fakeRows) Next() bool {
+	if r.pos >= len(r.data) {
+		return false
+	}
+	r.pos++
+	return true
+}
+
+func (r *fakeRows) Scan(dest ...interface{}) error {
+	row := r.data[r.pos-1]
+	if len(dest) != len(row) {
+		return sql.ErrNoRows
+	}
+	for i, d := range dest {
+		switch ptr := d.(type) {
+		case *string:
+			*ptr = row[i].(string)
+		case *int:
+			*ptr = row[i].(int)
+		case *interface{}:
+			*ptr = row[i]
+		default:
+			return sql.ErrNoRows
+		}
+	}
+	return nil
+}
+
+type widget struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestToSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"Name":          "name",
+		"ID":            "id",
+		"URL":           "url",
+		"UserID":        "user_id",
+		"FirstName":     "first_name",
+		"HTTPServer":    "http_server",
+		"already_snake": "already_snake",
+	}
+	for in, want := range cases {
+		if got := toSnakeCase(in); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestStructFieldsByColumnFallsBackToSnakeCase(t *testing.T) {
+	type noTags struct {
+		FirstName string
+	}
+	fields := structFieldsByColumn(reflect.TypeOf(noTags{}))
+	if _, ok := fields["first_name"]; !ok {
+		t.Fatalf("expected snake_case fallback for FirstName, got %v", fields)
+	}
+}
+
+func TestGetScansSingleRow(t *testing.T) {
+	rows := &fakeRows{
+		columns: []string{"id", "name"},
+		data:    [][]interface{}{{1, "alice"}},
+	}
+	var w widget
+	if err := scanOne(rows, &w); err != nil {
+		t.Fatalf("scanOne: %v", err)
+	}
+	if w.ID != 1 || w.Name != "alice" {
+		t.Fatalf("got %+v", w)
+	}
+}
+
+func TestGetReturnsErrNoRowsWhenEmpty(t *testing.T) {
+	rows := &fakeRows{columns: []string{"id", "name"}}
+	var w widget
+	if err := scanOne(rows, &w); err != sql.ErrNoRows {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestSelectScansAllRows(t *testing.T) {
+	rows := &fakeRows{
+		columns: []string{"id", "name"},
+		data: [][]interface{}{
+			{1, "alice"},
+			{2, "bob"},
+		},
+	}
+	var widgets []widget
+	if err := scanAll(rows, &widgets); err != nil {
+		t.Fatalf("scanAll: %v", err)
+	}
+	if len(widgets) != 2 || widgets[0].Name != "alice" || widgets[1].Name != "bob" {
+		t.Fatalf("got %+v", widgets)
+	}
+}
+
+func TestSelectScansPointerSlice(t *testing.T) {
+	rows := &fakeRows{
+		columns: []string{"id", "name"},
+		data:    [][]interface{}{{1, "alice"}},
+	}
+	var widgets []*widget
+	if err := scanAll(rows, &widgets); err != nil {
+		t.Fatalf("scanAll: %v", err)
+	}
+	if len(widgets) != 1 || widgets[0].Name != "alice" {
+		t.Fatalf("got %+v", widgets)
+	}
+}
@@ -213,3 +213,15 @@ func (r PgxRowsWrapper) Columns() ([]string, error) {
 
 	return cols, nil
 }
+
+// RawValues implements RawValueser, exposing the current row's unconverted
+// driver values.
+func (r PgxRowsWrapper) RawValues() [][]byte {
+	return r.Rows.RawValues()
+}
+
+// FieldDescriptions exposes pgx's richer field metadata for the current
+// result set, for callers that need more than Columns gives them.
+func (r PgxRowsWrapper) FieldDescriptions() []pgconn.FieldDescription {
+	return r.Rows.FieldDescriptions()
+}
@@ -0,0 +1,214 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// Span is the handle returned by Tracer.Start. It's closed once the traced
+// operation finishes -- immediately for Exec, or when the resulting Rows
+// is closed for a query.
+type Span interface {
+	// SetError records that the operation failed.
+	SetError(err error)
+	// SetRowsAffected records how many rows an Exec affected, or how many
+	// rows a query returned.
+	SetRowsAffected(n int64)
+	// End closes the span.
+	End()
+}
+
+// Tracer starts a Span for a single database operation. Implementations
+// adapt this to otel, datadog, or anything else; the default used by
+// WithTracing when no Tracer is supplied is a no-op.
+type Tracer interface {
+	Start(ctx context.Context, opName string, attrs map[string]interface{}) (context.Context, Span)
+}
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ map[string]interface{}) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetError(error)        {}
+func (noopSpan) SetRowsAffected(int64) {}
+func (noopSpan) End()                  {}
+
+// StatementFingerprinter strips literal values out of a SQL statement so
+// statements that differ only by literal collapse to one shape, keeping
+// span cardinality bounded.
+type StatementFingerprinter interface {
+	Fingerprint(query string) string
+}
+
+// DefaultFingerprinter is the StatementFingerprinter passed to
+// WithFingerprinting when callers don't have their own: it blanks out
+// quoted string literals and numeric literals, the same strategy
+// Question/Dollar/Colon/AtP in placeholder.go use to ship a ready instance
+// alongside the interface they implement.
+var DefaultFingerprinter StatementFingerprinter = defaultFingerprinter{}
+
+type defaultFingerprinter struct{}
+
+// Fingerprint implements StatementFingerprinter by blanking out quoted
+// string literals and numeric literals.
+func (defaultFingerprinter) Fingerprint(query string) string {
+	var b strings.Builder
+	n := len(query)
+	for i := 0; i < n; i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			b.WriteString("'?'")
+			j := i + 1
+			for j < n && query[j] != '\'' {
+				j++
+			}
+			i = j
+		case c >= '0' && c <= '9':
+			b.WriteByte('?')
+			j := i
+			for j < n && (query[j] >= '0' && query[j] <= '9' || query[j] == '.') {
+				j++
+			}
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// TraceOption configures WithTracing.
+type TraceOption func(*tracingRunner)
+
+// WithTracer sets the Tracer used to start spans. The default is a no-op.
+func WithTracer(t Tracer) TraceOption {
+	return func(r *tracingRunner) { r.tracer = t }
+}
+
+// WithFingerprinting enables statement fingerprinting (stripping literals)
+// in the db.statement attribute attached to each span.
+func WithFingerprinting(f StatementFingerprinter) TraceOption {
+	return func(r *tracingRunner) { r.fingerprinter = f }
+}
+
+// WithTracing wraps r so every ExecContext/QueryContext/QueryRowContext
+// call starts a span recording db.statement, db.system, the argument
+// count, and the eventual error/rows-affected/rows-returned outcome. For
+// queries the span stays open until the returned Rows is closed.
+func WithTracing(r RunnerContext, opts ...TraceOption) RunnerContext {
+	tr := &tracingRunner{RunnerContext: r, tracer: noopTracer{}, system: "sql"}
+	for _, opt := range opts {
+		opt(tr)
+	}
+	return tr
+}
+
+type tracingRunner struct {
+	RunnerContext
+	tracer        Tracer
+	fingerprinter StatementFingerprinter
+	system        string
+}
+
+func (r *tracingRunner) attrs(query string, nargs int) map[string]interface{} {
+	statement := query
+	if r.fingerprinter != nil {
+		statement = r.fingerprinter.Fingerprint(query)
+	}
+	return map[string]interface{}{
+		"db.statement":  statement,
+		"db.system":     r.system,
+		"db.args.count": nargs,
+	}
+}
+
+func (r *tracingRunner) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := r.tracer.Start(ctx, operationName(query), r.attrs(query, len(args)))
+	defer span.End()
+
+	res, err := r.RunnerContext.ExecContext(ctx, query, args...)
+	if err != nil {
+		span.SetError(err)
+		return res, err
+	}
+	if n, aerr := res.RowsAffected(); aerr == nil {
+		span.SetRowsAffected(n)
+	}
+	return res, err
+}
+
+func (r *tracingRunner) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	ctx, span := r.tracer.Start(ctx, operationName(query), r.attrs(query, len(args)))
+
+	rows, err := r.RunnerContext.QueryContext(ctx, query, args...)
+	if err != nil {
+		span.SetError(err)
+		span.End()
+		return nil, err
+	}
+	return &tracingRows{Rows: rows, span: span}, nil
+}
+
+func (r *tracingRunner) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	ctx, span := r.tracer.Start(ctx, operationName(query), r.attrs(query, len(args)))
+	return &tracingRow{RowScanner: r.RunnerContext.QueryRowContext(ctx, query, args...), span: span}
+}
+
+// tracingRows keeps the query span open until Close, tallying rows seen so
+// it can record SetRowsAffected even though pgx and database/sql never
+// tell us a row count up front.
+type tracingRows struct {
+	Rows
+	span Span
+	rows int64
+}
+
+func (r *tracingRows) Next() bool {
+	ok := r.Rows.Next()
+	if ok {
+		r.rows++
+	}
+	return ok
+}
+
+func (r *tracingRows) Close() {
+	if err := r.Rows.Err(); err != nil {
+		r.span.SetError(err)
+	}
+	r.span.SetRowsAffected(r.rows)
+	r.span.End()
+	r.Rows.Close()
+}
+
+type tracingRow struct {
+	RowScanner
+	span Span
+}
+
+func (r *tracingRow) Scan(dest ...interface{}) error {
+	err := r.RowScanner.Scan(dest...)
+	if err != nil {
+		r.span.SetError(err)
+	}
+	r.span.End()
+	return err
+}
+
+// operationName extracts the first keyword of query (SELECT, INSERT, ...)
+// to use as the span name, falling back to "sql" if query is empty.
+func operationName(query string) string {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "sql"
+	}
+	if end := strings.IndexAny(query, " \t\n("); end >= 0 {
+		return query[:end]
+	}
+	return query
+}
@@ -0,0 +1,195 @@
+package squirrel
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+// fakeSpan records every call WithTracing makes against it, so tests can
+// assert on the outcome a traced operation reports.
+type fakeSpan struct {
+	err     error
+	rows    int64
+	rowsSet bool
+	ended   bool
+}
+
+func (s *fakeSpan) SetError(err error)      { s.err = err }
+func (s *fakeSpan) SetRowsAffected(n int64) { s.rows = n; s.rowsSet = true }
+func (s *fakeSpan) End()                    { s.ended = true }
+
+// fakeTracer is a Tracer that hands out fakeSpans and records the opName and
+// attrs it was started with, so tests can inspect what WithTracing reports
+// without a real otel/datadog backend.
+type fakeTracer struct {
+	names []string
+	attrs []map[string]interface{}
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Start(ctx context.Context, opName string, attrs map[string]interface{}) (context.Context, Span) {
+	span := &fakeSpan{}
+	t.names = append(t.names, opName)
+	t.attrs = append(t.attrs, attrs)
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func (t *fakeTracer) last() *fakeSpan { return t.spans[len(t.spans)-1] }
+
+type fakeResult struct{ rows int64 }
+
+func (r fakeResult) LastInsertId() (int64, error) { return 0, nil }
+func (r fakeResult) RowsAffected() (int64, error) { return r.rows, nil }
+
+type fakeRowScanner struct{ err error }
+
+func (s fakeRowScanner) Scan(dest ...interface{}) error { return s.err }
+
+// fakeRunnerContext is a scripted RunnerContext standing in for a real
+// database connection, so tracingRunner can be exercised without one.
+type fakeRunnerContext struct {
+	execResult sql.Result
+	execErr    error
+	rows       Rows
+	queryErr   error
+	rowScanner RowScanner
+}
+
+func (f fakeRunnerContext) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return f.execResult, f.execErr
+}
+
+func (f fakeRunnerContext) QueryContext(ctx context.Context, query string, args ...interface{}) (Rows, error) {
+	return f.rows, f.queryErr
+}
+
+func (f fakeRunnerContext) QueryRowContext(ctx context.Context, query string, args ...interface{}) RowScanner {
+	return f.rowScanner
+}
+
+func TestWithTracingExecContextRecordsRowsAffected(t *testing.T) {
+	tracer := &fakeTracer{}
+	r := WithTracing(fakeRunnerContext{execResult: fakeResult{rows: 3}}, WithTracer(tracer))
+
+	if _, err := r.ExecContext(context.Background(), "insert into widgets (name) values (?)", "a"); err != nil {
+		t.Fatalf("ExecContext: %v", err)
+	}
+	span := tracer.last()
+	if !span.ended {
+		t.Fatal("expected span to be ended after ExecContext returns")
+	}
+	if !span.rowsSet || span.rows != 3 {
+		t.Fatalf("span.rows = %v (set=%v), want 3", span.rows, span.rowsSet)
+	}
+	if tracer.names[0] != "insert" {
+		t.Fatalf("operation name = %q, want %q", tracer.names[0], "insert")
+	}
+}
+
+func TestWithTracingExecContextRecordsError(t *testing.T) {
+	tracer := &fakeTracer{}
+	wantErr := errors.New("boom")
+	r := WithTracing(fakeRunnerContext{execErr: wantErr}, WithTracer(tracer))
+
+	if _, err := r.ExecContext(context.Background(), "delete from widgets", nil); err != wantErr {
+		t.Fatalf("ExecContext: got %v, want %v", err, wantErr)
+	}
+	if tracer.last().err != wantErr {
+		t.Fatalf("span.err = %v, want %v", tracer.last().err, wantErr)
+	}
+}
+
+func TestWithTracingQueryContextKeepsSpanOpenUntilRowsClose(t *testing.T) {
+	tracer := &fakeTracer{}
+	rows := &fakeRows{
+		columns: []string{"id"},
+		data:    [][]interface{}{{1}, {2}},
+	}
+	r := WithTracing(fakeRunnerContext{rows: rows}, WithTracer(tracer))
+
+	got, err := r.QueryContext(context.Background(), "select id from widgets", nil)
+	if err != nil {
+		t.Fatalf("QueryContext: %v", err)
+	}
+	span := tracer.last()
+	if span.ended {
+		t.Fatal("expected span to stay open until Close")
+	}
+
+	for got.Next() {
+	}
+	if span.ended {
+		t.Fatal("expected span to stay open until Close, not just after Next returns false")
+	}
+
+	got.Close()
+	if !span.ended {
+		t.Fatal("expected Close to end the span")
+	}
+	if !span.rowsSet || span.rows != 2 {
+		t.Fatalf("span.rows = %v (set=%v), want 2", span.rows, span.rowsSet)
+	}
+}
+
+func TestWithTracingQueryRowContextEndsSpanOnScan(t *testing.T) {
+	tracer := &fakeTracer{}
+	wantErr := errors.New("no rows")
+	r := WithTracing(fakeRunnerContext{rowScanner: fakeRowScanner{err: wantErr}}, WithTracer(tracer))
+
+	row := r.QueryRowContext(context.Background(), "select id from widgets where id = ?", 1)
+	span := tracer.last()
+	if span.ended {
+		t.Fatal("expected span to stay open until Scan is called")
+	}
+
+	if err := row.Scan(); err != wantErr {
+		t.Fatalf("Scan: got %v, want %v", err, wantErr)
+	}
+	if !span.ended {
+		t.Fatal("expected Scan to end the span")
+	}
+	if span.err != wantErr {
+		t.Fatalf("span.err = %v, want %v", span.err, wantErr)
+	}
+}
+
+func TestWithFingerprintingReplacesStatementAttr(t *testing.T) {
+	tracer := &fakeTracer{}
+	r := WithTracing(fakeRunnerContext{execResult: fakeResult{}}, WithTracer(tracer), WithFingerprinting(DefaultFingerprinter))
+
+	query := "select * from widgets where id = 42"
+	if _, err := r.ExecContext(context.Background(), query, nil); err != nil {
+		t.Fatalf("ExecContext: %v", err)
+	}
+	got := tracer.attrs[0]["db.statement"]
+	want := DefaultFingerprinter.Fingerprint(query)
+	if got != want {
+		t.Fatalf("db.statement = %q, want %q", got, want)
+	}
+}
+
+func TestOperationNameExtractsLeadingKeyword(t *testing.T) {
+	cases := map[string]string{
+		"SELECT * FROM widgets":   "SELECT",
+		"insert into widgets(id)": "insert",
+		"update(widgets) set a=1": "update",
+		"":                        "sql",
+		"   ":                     "sql",
+	}
+	for query, want := range cases {
+		if got := operationName(query); got != want {
+			t.Errorf("operationName(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+func TestDefaultFingerprinterBlanksLiteralsAndNumbers(t *testing.T) {
+	got := DefaultFingerprinter.Fingerprint("select * from widgets where name = 'alice' and age > 42")
+	want := "select * from widgets where name = '?' and age > ?"
+	if got != want {
+		t.Fatalf("Fingerprint() = %q, want %q", got, want)
+	}
+}